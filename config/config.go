@@ -0,0 +1,152 @@
+// Package config loads MaxCDN credentials the same way across tools
+// (maxpurge, maxcurl, maxreport, ...), so none of them need their own
+// bespoke os.Getenv fallback logic. Credentials are resolved from CLI
+// flags, then the MAXCDN_* environment variables, then a named profile
+// in a YAML config file, AWS-CLI style.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// defaultProfile is used when Load is called with an empty profile
+// name.
+const defaultProfile = "default"
+
+// Credentials holds everything needed to construct a maxcdn.MaxCDN.
+type Credentials struct {
+	Alias  string
+	Token  string
+	Secret string
+}
+
+// Profile is a single named set of credentials in a config file.
+type Profile struct {
+	Alias  string `yaml:"alias"`
+	Token  string `yaml:"token"`
+	Secret string `yaml:"secret"`
+}
+
+// File is the on-disk shape of a maxcdn config file.
+type File struct {
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Load resolves credentials for profile (defaultProfile if empty),
+// merging flags over the MAXCDN_* environment variables over the legacy
+// unprefixed ALIAS/TOKEN/SECRET variables over the named profile in the
+// first config file found. It returns an error if, after merging all of
+// these sources, any field is still empty.
+func Load(profile string, flags Credentials) (Credentials, error) {
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	creds := merge(flags, Credentials{
+		Alias:  os.Getenv("MAXCDN_ALIAS"),
+		Token:  os.Getenv("MAXCDN_TOKEN"),
+		Secret: os.Getenv("MAXCDN_SECRET"),
+	})
+
+	// Fall back to the unprefixed ALIAS/TOKEN/SECRET env vars that
+	// maxpurge read directly before it adopted this package, so
+	// existing deployments aren't silently broken by the rename.
+	creds = merge(creds, Credentials{
+		Alias:  os.Getenv("ALIAS"),
+		Token:  os.Getenv("TOKEN"),
+		Secret: os.Getenv("SECRET"),
+	})
+
+	if file, err := readFile(configPath()); err == nil {
+		if p, ok := file.Profiles[profile]; ok {
+			creds = merge(creds, Credentials(p))
+		}
+	}
+
+	return creds, creds.validate()
+}
+
+// merge returns primary with any empty field filled in from fallback.
+func merge(primary, fallback Credentials) Credentials {
+	if primary.Alias == "" {
+		primary.Alias = fallback.Alias
+	}
+	if primary.Token == "" {
+		primary.Token = fallback.Token
+	}
+	if primary.Secret == "" {
+		primary.Secret = fallback.Secret
+	}
+	return primary
+}
+
+func (c Credentials) validate() error {
+	var missing []string
+	if c.Alias == "" {
+		missing = append(missing, "alias")
+	}
+	if c.Token == "" {
+		missing = append(missing, "token")
+	}
+	if c.Secret == "" {
+		missing = append(missing, "secret")
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("config: missing %s (set via flag, MAXCDN_* env var, or config file)", strings.Join(missing, ", "))
+}
+
+// configPath returns the first config file that exists, checking
+// $XDG_CONFIG_HOME/maxcdn/config.yaml, ~/.config/maxcdn/config.yaml,
+// then ~/.maxcdn.yaml. It returns "" if none exist.
+func configPath() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		if p := filepath.Join(xdg, "maxcdn", "config.yaml"); exists(p) {
+			return p
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	if p := filepath.Join(home, ".config", "maxcdn", "config.yaml"); exists(p) {
+		return p
+	}
+	if p := filepath.Join(home, ".maxcdn.yaml"); exists(p) {
+		return p
+	}
+
+	return ""
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func readFile(path string) (*File, error) {
+	if path == "" {
+		return nil, os.ErrNotExist
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := new(File)
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, dir, contents string) {
+	t.Helper()
+
+	path := filepath.Join(dir, "maxcdn", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func clearCredentialEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"MAXCDN_ALIAS", "MAXCDN_TOKEN", "MAXCDN_SECRET", "ALIAS", "TOKEN", "SECRET"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	cases := []struct {
+		name     string
+		primary  Credentials
+		fallback Credentials
+		want     Credentials
+	}{
+		{
+			name:     "primary wins when set",
+			primary:  Credentials{Alias: "a", Token: "t", Secret: "s"},
+			fallback: Credentials{Alias: "fa", Token: "ft", Secret: "fs"},
+			want:     Credentials{Alias: "a", Token: "t", Secret: "s"},
+		},
+		{
+			name:     "fallback fills empty fields",
+			primary:  Credentials{Alias: "a"},
+			fallback: Credentials{Alias: "fa", Token: "ft", Secret: "fs"},
+			want:     Credentials{Alias: "a", Token: "ft", Secret: "fs"},
+		},
+		{
+			name:     "both empty stays empty",
+			primary:  Credentials{},
+			fallback: Credentials{},
+			want:     Credentials{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := merge(c.primary, c.fallback); got != c.want {
+				t.Fatalf("merge(%+v, %+v) = %+v, want %+v", c.primary, c.fallback, got, c.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	clearCredentialEnv(t)
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	writeConfigFile(t, dir, "profiles:\n  default:\n    alias: file-alias\n    token: file-token\n    secret: file-secret\n")
+
+	// A flag beats the environment, which beats the config file.
+	t.Setenv("MAXCDN_ALIAS", "env-alias")
+	t.Setenv("MAXCDN_TOKEN", "env-token")
+
+	creds, err := Load("", Credentials{Alias: "flag-alias"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Credentials{Alias: "flag-alias", Token: "env-token", Secret: "file-secret"}
+	if creds != want {
+		t.Fatalf("Load() = %+v, want %+v", creds, want)
+	}
+}
+
+func TestLoad_FallsBackToLegacyEnvVars(t *testing.T) {
+	clearCredentialEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	t.Setenv("ALIAS", "legacy-alias")
+	t.Setenv("TOKEN", "legacy-token")
+	t.Setenv("SECRET", "legacy-secret")
+
+	creds, err := Load("", Credentials{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Credentials{Alias: "legacy-alias", Token: "legacy-token", Secret: "legacy-secret"}
+	if creds != want {
+		t.Fatalf("Load() = %+v, want %+v", creds, want)
+	}
+}
+
+func TestLoad_NamedProfile(t *testing.T) {
+	clearCredentialEnv(t)
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	writeConfigFile(t, dir, "profiles:\n  prod:\n    alias: prod-alias\n    token: prod-token\n    secret: prod-secret\n")
+
+	creds, err := Load("prod", Credentials{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Credentials{Alias: "prod-alias", Token: "prod-token", Secret: "prod-secret"}
+	if creds != want {
+		t.Fatalf("Load() = %+v, want %+v", creds, want)
+	}
+}
+
+func TestLoad_MissingOrMalformedFileFallsBackSilently(t *testing.T) {
+	clearCredentialEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("MAXCDN_ALIAS", "env-alias")
+	t.Setenv("MAXCDN_TOKEN", "env-token")
+	t.Setenv("MAXCDN_SECRET", "env-secret")
+
+	// No config file at all: Load should still succeed from env alone.
+	creds, err := Load("", Credentials{})
+	if err != nil {
+		t.Fatalf("unexpected error with no config file: %v", err)
+	}
+	want := Credentials{Alias: "env-alias", Token: "env-token", Secret: "env-secret"}
+	if creds != want {
+		t.Fatalf("Load() = %+v, want %+v", creds, want)
+	}
+
+	// A malformed config file shouldn't stop Load from falling back to
+	// the other sources, since env/flags already satisfy validate().
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	writeConfigFile(t, dir, "not: [valid: yaml")
+
+	creds, err = Load("", Credentials{})
+	if err != nil {
+		t.Fatalf("unexpected error with malformed config file: %v", err)
+	}
+	if creds != want {
+		t.Fatalf("Load() = %+v, want %+v", creds, want)
+	}
+}
+
+func TestLoad_MissingFieldsError(t *testing.T) {
+	clearCredentialEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	_, err := Load("", Credentials{Alias: "only-alias"})
+	if err == nil {
+		t.Fatal("expected an error for missing token and secret")
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	t.Run("prefers XDG_CONFIG_HOME", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", dir)
+		writeConfigFile(t, dir, "profiles: {}\n")
+
+		want := filepath.Join(dir, "maxcdn", "config.yaml")
+		if got := configPath(); got != want {
+			t.Fatalf("configPath() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("empty when nothing exists", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		t.Setenv("HOME", t.TempDir())
+
+		if got := configPath(); got != "" {
+			t.Fatalf("configPath() = %q, want empty", got)
+		}
+	})
+}
+
+func TestReadFile(t *testing.T) {
+	t.Run("missing path", func(t *testing.T) {
+		if _, err := readFile(""); err == nil {
+			t.Fatal("expected an error for an empty path")
+		}
+	})
+
+	t.Run("malformed yaml", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte("not: [valid: yaml"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := readFile(path); err == nil {
+			t.Fatal("expected an error for malformed yaml")
+		}
+	})
+
+	t.Run("valid file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		contents := "profiles:\n  default:\n    alias: a\n    token: t\n    secret: s\n"
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := readFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := Profile{Alias: "a", Token: "t", Secret: "s"}
+		if got := file.Profiles["default"]; got != want {
+			t.Fatalf("Profiles[\"default\"] = %+v, want %+v", got, want)
+		}
+	})
+}
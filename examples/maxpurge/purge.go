@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/codegangsta/cli"
+	"github.com/customerio/go-maxcdn/config"
 	"github.com/jmervine/go-maxcdn"
 )
 
-var alias, token, secret, zone, file string
+var creds config.Credentials
+var zone, file string
 var start time.Time
 
 func init() {
@@ -32,17 +34,27 @@ Options:
 	cli.HelpPrinter = helpPrinter
 
 	app.Flags = []cli.Flag{
-		cli.StringFlag{"alias, a", "", "[required] consumer alias"},
-		cli.StringFlag{"token, t", "", "[required] consumer token"},
-		cli.StringFlag{"secret, s", "", "[required] consumer secret"},
+		cli.StringFlag{"alias, a", "", "consumer alias (overrides env/config)"},
+		cli.StringFlag{"token, t", "", "consumer token (overrides env/config)"},
+		cli.StringFlag{"secret, s", "", "consumer secret (overrides env/config)"},
+		cli.StringFlag{"profile", "", "named credentials profile from config file"},
 		cli.StringFlag{"zone, z", "", "[required] zone to be purged"},
 		cli.StringFlag{"file, f", "", "cached file to be purged"},
 	}
 
 	app.Action = func(c *cli.Context) {
-		alias = ensureArg(c.String("alias"), "ALIAS", c)
-		token = ensureArg(c.String("token"), "TOKEN", c)
-		secret = ensureArg(c.String("secret"), "SECRET", c)
+		var err error
+		creds, err = config.Load(c.String("profile"), config.Credentials{
+			Alias:  c.String("alias"),
+			Token:  c.String("token"),
+			Secret: c.String("secret"),
+		})
+		if err != nil {
+			fmt.Println(err)
+			cli.ShowAppHelp(c)
+			os.Exit(2)
+		}
+
 		zone = ensureArg(c.String("zone"), "ZONE", c)
 		file = c.String("file")
 	}
@@ -53,7 +65,7 @@ Options:
 }
 
 func main() {
-	max := maxcdn.NewMaxCDN(alias, token, secret)
+	max := maxcdn.NewMaxCDN(creds.Alias, creds.Token, creds.Secret)
 
 	i, err := strconv.ParseInt(zone, 0, 64)
 	check(err)
@@ -101,4 +113,4 @@ func helpPrinter(templ string, data interface{}) {
 	}
 	w.Flush()
 	os.Exit(0)
-}
\ No newline at end of file
+}
@@ -5,13 +5,13 @@
 package maxcdn
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
-	"sync"
 
 	"github.com/garyburd/go-oauth/oauth"
 )
@@ -32,11 +32,28 @@ type MaxCDN struct {
 	Alias      string
 	client     oauth.Client
 	HTTPClient *http.Client
+
+	// Retry configures automatic retrying of idempotent requests
+	// (GET/PUT/DELETE) in DoContext. It's opt-in: the zero value
+	// disables retrying.
+	Retry RetryConfig
+
+	// Concurrency bounds how many requests PurgeZonesContext and
+	// PurgeFilesContext issue in parallel. It defaults to
+	// defaultPurgeConcurrency when zero or negative.
+	Concurrency int
+
+	// middleware is the chain of http.RoundTripper wrappers registered
+	// via Use, applied on top of HTTPClient's own Transport.
+	middleware []func(http.RoundTripper) http.RoundTripper
+
+	// Reports provides typed access to MaxCDN's reporting endpoints.
+	Reports *Reports
 }
 
 // NewMaxCDN sets up a new MaxCDN instance.
 func NewMaxCDN(alias, token, secret string) *MaxCDN {
-	return &MaxCDN{
+	max := &MaxCDN{
 		HTTPClient: http.DefaultClient,
 		Alias:      alias,
 		client: oauth.Client{
@@ -48,12 +65,21 @@ func NewMaxCDN(alias, token, secret string) *MaxCDN {
 			TokenRequestURI:               APIHost + "oauth/access_token",
 		},
 	}
+	max.Reports = &Reports{max: max}
+	return max
 }
 
 // Get does an OAuth signed http.Get
-func (max *MaxCDN) Get(endpoint string, form url.Values) (mapper *GenericResponse, err error) {
+func (max *MaxCDN) Get(endpoint string, form url.Values) (*GenericResponse, error) {
+	return max.GetContext(context.Background(), endpoint, form)
+}
+
+// GetContext does an OAuth signed http.Get bound to ctx. The request is
+// aborted if ctx is cancelled or its deadline elapses before a response is
+// received.
+func (max *MaxCDN) GetContext(ctx context.Context, endpoint string, form url.Values) (mapper *GenericResponse, err error) {
 	mapper = new(GenericResponse)
-	raw, res, err := max.Do("GET", endpoint, form)
+	raw, res, err := max.DoContext(ctx, "GET", endpoint, form)
 	mapper.Response = res
 	if err != nil {
 		return
@@ -64,9 +90,16 @@ func (max *MaxCDN) Get(endpoint string, form url.Values) (mapper *GenericRespons
 }
 
 // Post does an OAuth signed http.Post
-func (max *MaxCDN) Post(endpoint string, form url.Values) (mapper *GenericResponse, err error) {
+func (max *MaxCDN) Post(endpoint string, form url.Values) (*GenericResponse, error) {
+	return max.PostContext(context.Background(), endpoint, form)
+}
+
+// PostContext does an OAuth signed http.Post bound to ctx. The request is
+// aborted if ctx is cancelled or its deadline elapses before a response is
+// received.
+func (max *MaxCDN) PostContext(ctx context.Context, endpoint string, form url.Values) (mapper *GenericResponse, err error) {
 	mapper = new(GenericResponse)
-	raw, res, err := max.Do("POST", endpoint, form)
+	raw, res, err := max.DoContext(ctx, "POST", endpoint, form)
 	mapper.Response = res
 	if err != nil {
 		return
@@ -77,9 +110,16 @@ func (max *MaxCDN) Post(endpoint string, form url.Values) (mapper *GenericRespon
 }
 
 // Put does an OAuth signed http.Put
-func (max *MaxCDN) Put(endpoint string, form url.Values) (mapper *GenericResponse, err error) {
+func (max *MaxCDN) Put(endpoint string, form url.Values) (*GenericResponse, error) {
+	return max.PutContext(context.Background(), endpoint, form)
+}
+
+// PutContext does an OAuth signed http.Put bound to ctx. The request is
+// aborted if ctx is cancelled or its deadline elapses before a response is
+// received.
+func (max *MaxCDN) PutContext(ctx context.Context, endpoint string, form url.Values) (mapper *GenericResponse, err error) {
 	mapper = new(GenericResponse)
-	raw, res, err := max.Do("PUT", endpoint, form)
+	raw, res, err := max.DoContext(ctx, "PUT", endpoint, form)
 	mapper.Response = res
 	if err != nil {
 		return
@@ -90,9 +130,16 @@ func (max *MaxCDN) Put(endpoint string, form url.Values) (mapper *GenericRespons
 }
 
 // Delete does an OAuth signed http.Delete
-func (max *MaxCDN) Delete(endpoint string) (mapper *GenericResponse, err error) {
+func (max *MaxCDN) Delete(endpoint string) (*GenericResponse, error) {
+	return max.DeleteContext(context.Background(), endpoint)
+}
+
+// DeleteContext does an OAuth signed http.Delete bound to ctx. The request
+// is aborted if ctx is cancelled or its deadline elapses before a response
+// is received.
+func (max *MaxCDN) DeleteContext(ctx context.Context, endpoint string) (mapper *GenericResponse, err error) {
 	mapper = new(GenericResponse)
-	raw, res, err := max.Do("DELETE", endpoint, nil)
+	raw, res, err := max.DoContext(ctx, "DELETE", endpoint, nil)
 	mapper.Response = res
 	if err != nil {
 		return
@@ -104,57 +151,28 @@ func (max *MaxCDN) Delete(endpoint string) (mapper *GenericResponse, err error)
 
 // PurgeZone purges a specified zones cache.
 func (max *MaxCDN) PurgeZone(zone int) (*GenericResponse, error) {
-	return max.Delete(fmt.Sprintf("/zones/pull.json/%d/cache", zone))
+	return max.PurgeZoneContext(context.Background(), zone)
 }
 
-// PurgeZones purges multiple zones caches.
-func (max *MaxCDN) PurgeZones(zones []int) (responses []GenericResponse, last error) {
-	var rc chan *GenericResponse
-	var ec chan error
-
-	waiter := sync.WaitGroup{}
-	mutex := sync.Mutex{}
-
-	done := func() {
-		waiter.Done()
-	}
-
-	send := func(zone int) {
-		defer done()
-		r, e := max.PurgeZone(zone)
-
-		rc <- r
-		ec <- e
-	}
-
-	collect := func() {
-		defer done()
-		r := <-rc
-		e := <-ec
-
-		mutex.Lock()
-		responses = append(responses, *r)
-		last = e
-		mutex.Unlock()
-	}
-
-	for _, zone := range zones {
-		waiter.Add(2)
-		go send(zone)
-		go collect()
-	}
-
-	waiter.Wait()
-	return
+// PurgeZoneContext purges a specified zones cache, aborting if ctx is
+// cancelled or its deadline elapses before a response is received.
+func (max *MaxCDN) PurgeZoneContext(ctx context.Context, zone int) (*GenericResponse, error) {
+	return max.DeleteContext(ctx, fmt.Sprintf("/zones/pull.json/%d/cache", zone))
 }
 
 // PurgeFile purges a specified file by zone from cache.
-func (max *MaxCDN) PurgeFile(zone int, file string) (mapper *GenericResponse, err error) {
+func (max *MaxCDN) PurgeFile(zone int, file string) (*GenericResponse, error) {
+	return max.PurgeFileContext(context.Background(), zone, file)
+}
+
+// PurgeFileContext purges a specified file by zone from cache, aborting if
+// ctx is cancelled or its deadline elapses before a response is received.
+func (max *MaxCDN) PurgeFileContext(ctx context.Context, zone int, file string) (mapper *GenericResponse, err error) {
 	form := url.Values{}
 	form.Set("file", file)
 
 	mapper = new(GenericResponse)
-	raw, res, err := max.Do("DELETE", fmt.Sprintf("/zones/pull.json/%d/cache", zone), form)
+	raw, res, err := max.DoContext(ctx, "DELETE", fmt.Sprintf("/zones/pull.json/%d/cache", zone), form)
 	mapper.Response = res
 	if err != nil {
 		return
@@ -164,47 +182,6 @@ func (max *MaxCDN) PurgeFile(zone int, file string) (mapper *GenericResponse, er
 	return
 }
 
-// PurgeFiles purges multiple files from a zone.
-func (max *MaxCDN) PurgeFiles(zone int, files []string) (responses []GenericResponse, last error) {
-	var rc chan *GenericResponse
-	var ec chan error
-
-	waiter := sync.WaitGroup{}
-	mutex := sync.Mutex{}
-
-	done := func() {
-		waiter.Done()
-	}
-
-	send := func(file string) {
-		defer done()
-		r, e := max.PurgeFile(zone, file)
-
-		rc <- r
-		ec <- e
-	}
-
-	collect := func() {
-		defer done()
-		r := <-rc
-		e := <-ec
-
-		mutex.Lock()
-		responses = append(responses, *r)
-		last = e
-		mutex.Unlock()
-	}
-
-	for _, file := range files {
-		waiter.Add(2)
-		go send(file)
-		go collect()
-	}
-
-	waiter.Wait()
-	return
-}
-
 func (max *MaxCDN) url(endpoint string) string {
 	endpoint = strings.TrimPrefix(endpoint, "/")
 	return fmt.Sprintf("%s/%s/%s", APIHost, max.Alias, endpoint)
@@ -218,9 +195,31 @@ func (max *MaxCDN) url(endpoint string) string {
 // responses are planned for future versions, but there are too many make
 // it worth implementing all of them, so this support should remain.
 func (max *MaxCDN) Do(method, endpoint string, form url.Values) (raw []byte, res *http.Response, err error) {
+	return max.DoContext(context.Background(), method, endpoint, form)
+}
+
+// DoContext is the context-aware variant of Do. It's used by all other
+// Context methods, and is the place to plug in request deadlines,
+// cancellation, and tracing/cancellation propagated from upstream HTTP
+// handlers.
+//
+// If max.Retry.MaxRetries is greater than 0 and method is idempotent
+// (GET, PUT, DELETE), the request is retried per max.Retry on 5xx
+// responses or transient errors. POST is never retried here; use
+// DoWithRetry to opt a POST into retrying explicitly.
+func (max *MaxCDN) DoContext(ctx context.Context, method, endpoint string, form url.Values) (raw []byte, res *http.Response, err error) {
+	if max.Retry.MaxRetries > 0 && idempotentMethods[method] {
+		return max.doWithRetry(ctx, method, endpoint, form, max.Retry)
+	}
+	return max.doOnce(ctx, method, endpoint, form)
+}
+
+// doOnce performs a single, non-retried request. It's the primitive that
+// both DoContext and the retry layer in retry.go build on.
+func (max *MaxCDN) doOnce(ctx context.Context, method, endpoint string, form url.Values) (raw []byte, res *http.Response, err error) {
 	var req *http.Request
 
-	req, err = http.NewRequest(method, max.url(endpoint), nil)
+	req, err = http.NewRequestWithContext(ctx, method, max.url(endpoint), nil)
 	if err != nil {
 		return
 	}
@@ -246,7 +245,10 @@ func (max *MaxCDN) Do(method, endpoint string, form url.Values) (raw []byte, res
 	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("User-Agent", userAgent)
 
-	res, err = max.HTTPClient.Do(req)
+	res, err = max.httpClient().Do(req)
+	if err != nil {
+		return nil, res, err
+	}
 	defer res.Body.Close()
 
 	raw, err = ioutil.ReadAll(res.Body)
@@ -0,0 +1,49 @@
+package maxcdn
+
+import "net/http"
+
+// Use registers mw in the HTTP round-trip chain used by all requests.
+// Each middleware wraps the ones registered before it, so the
+// last-registered middleware is outermost and runs first, while the
+// first-registered middleware is innermost and runs closest to the
+// wire. Use is typically called once, right after NewMaxCDN, before
+// any requests are made.
+func (max *MaxCDN) Use(mw func(http.RoundTripper) http.RoundTripper) {
+	max.middleware = append(max.middleware, mw)
+}
+
+// roundTripper builds the effective http.RoundTripper for a request:
+// the HTTPClient's own Transport (or http.DefaultTransport, if unset)
+// wrapped by every middleware registered via Use, in registration order.
+func (max *MaxCDN) roundTripper() http.RoundTripper {
+	var rt http.RoundTripper = http.DefaultTransport
+	if max.HTTPClient != nil && max.HTTPClient.Transport != nil {
+		rt = max.HTTPClient.Transport
+	}
+
+	for _, mw := range max.middleware {
+		rt = mw(rt)
+	}
+
+	return rt
+}
+
+// httpClient returns the *http.Client to issue a request with, wiring in
+// any middleware registered via Use without mutating max.HTTPClient.
+func (max *MaxCDN) httpClient() *http.Client {
+	if len(max.middleware) == 0 {
+		return max.HTTPClient
+	}
+
+	client := *max.HTTPClient
+	client.Transport = max.roundTripper()
+	return &client
+}
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface,
+// mirroring the standard library's http.HandlerFunc pattern.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,42 @@
+package maxcdn
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// redactedHeaderValue replaces a sensitive header's value in logs.
+const redactedHeaderValue = "[REDACTED]"
+
+// LoggingMiddleware returns Use-able middleware that logs each request's
+// method, URL, headers, status code, and duration to logger. The
+// Authorization header is redacted by default; set redactAuth to false
+// to log it as sent (not recommended outside of local debugging).
+//
+// Redaction only ever affects the headers value used for logging: the
+// request actually sent to MaxCDN keeps its real, signed Authorization
+// header.
+func LoggingMiddleware(logger *log.Logger, redactAuth bool) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			headers := req.Header
+			if redactAuth && headers.Get("Authorization") != "" {
+				headers = headers.Clone()
+				headers.Set("Authorization", redactedHeaderValue)
+			}
+
+			start := time.Now()
+			res, err := next.RoundTrip(req)
+			duration := time.Since(start)
+
+			if err != nil {
+				logger.Printf("maxcdn: %s %s headers=%v failed after %v: %v", req.Method, req.URL, headers, duration, err)
+				return res, err
+			}
+
+			logger.Printf("maxcdn: %s %s headers=%v -> %d in %v", req.Method, req.URL, headers, res.StatusCode, duration)
+			return res, err
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package maxcdn
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsRecorder receives observations about each request made through
+// the middleware chain. It's deliberately minimal so callers can adapt
+// it to prometheus/client_golang or any other metrics library without
+// this package importing it directly.
+type MetricsRecorder interface {
+	// ObserveRequest is called for every completed request, labeled by
+	// endpoint and method.
+	ObserveRequest(method, endpoint string, status int, duration time.Duration)
+
+	// ObserveError is called instead of ObserveRequest when the round
+	// trip itself failed (no status code available).
+	ObserveError(method, endpoint string)
+}
+
+// MetricsMiddleware returns Use-able middleware that reports every
+// request to recorder, labeled by a normalized endpoint rather than the
+// raw request path.
+func MetricsMiddleware(recorder MetricsRecorder) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			endpoint := normalizedEndpoint(req.URL.Path)
+
+			start := time.Now()
+			res, err := next.RoundTrip(req)
+
+			if err != nil {
+				recorder.ObserveError(req.Method, endpoint)
+				return res, err
+			}
+
+			recorder.ObserveRequest(req.Method, endpoint, res.StatusCode, time.Since(start))
+			return res, err
+		})
+	}
+}
+
+// normalizedEndpoint collapses path segments that are purely numeric
+// (zone IDs, page numbers, ...) to ":id", so per-call identifiers don't
+// explode the cardinality of endpoint-labeled metrics. For example,
+// "/alias/zones/pull.json/12345/cache" becomes
+// "/alias/zones/pull.json/:id/cache".
+func normalizedEndpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if seg != "" && isNumeric(seg) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package maxcdn
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"testing"
+)
+
+type recordingRoundTripper struct {
+	gotAuth string
+}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.gotAuth = req.Header.Get("Authorization")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(nil),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestLoggingMiddleware_DoesNotRedactTheWireRequest(t *testing.T) {
+	wire := &recordingRoundTripper{}
+	logger := log.New(ioutil.Discard, "", 0)
+
+	rt := LoggingMiddleware(logger, true)(wire)
+
+	req, _ := http.NewRequest("GET", "http://example.test/zones.json", nil)
+	req.Header.Set("Authorization", "OAuth signed-value")
+
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if wire.gotAuth != "OAuth signed-value" {
+		t.Fatalf("wire request Authorization header was tampered with, got %q", wire.gotAuth)
+	}
+}
+
+func TestUse_LastRegisteredRunsFirst(t *testing.T) {
+	max := NewMaxCDN("alias", "token", "secret")
+	max.HTTPClient = &http.Client{Transport: &recordingRoundTripper{}}
+
+	var order []string
+	mw := func(name string) func(http.RoundTripper) http.RoundTripper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	max.Use(mw("inner"))
+	max.Use(mw("outer"))
+
+	req, _ := http.NewRequest("GET", "http://example.test/zones.json", nil)
+	if _, err := max.roundTripper().RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Fatalf("expected [outer inner], got %v", order)
+	}
+}
@@ -0,0 +1,29 @@
+package maxcdn
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer starts a span around a single API call. It's deliberately
+// minimal so callers can adapt it to go.opentelemetry.io/otel or any
+// other tracing library without this package importing it directly.
+type Tracer interface {
+	// StartSpan starts a span named name as a child of ctx, returning
+	// the span's context and a function to end it. The end function is
+	// called with the round trip's error, if any.
+	StartSpan(ctx context.Context, name string) (context.Context, func(error))
+}
+
+// TracingMiddleware returns Use-able middleware that wraps each request
+// in a span, named "maxcdn.<method> <path>", started via tracer.
+func TracingMiddleware(tracer Tracer) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx, end := tracer.StartSpan(req.Context(), "maxcdn."+req.Method+" "+req.URL.Path)
+			res, err := next.RoundTrip(req.WithContext(ctx))
+			end(err)
+			return res, err
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package maxcdn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultPurgeConcurrency is used by PurgeZonesContext and
+// PurgeFilesContext when MaxCDN.Concurrency is unset.
+const defaultPurgeConcurrency = 8
+
+// PurgeError describes a single failed purge within a batch issued by
+// PurgeZonesContext or PurgeFilesContext.
+type PurgeError struct {
+	Zone int    // Zone is always set.
+	File string // File is set for per-file purges, empty for zone purges.
+	Err  error
+}
+
+func (e PurgeError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("zone %d, file %q: %v", e.Zone, e.File, e.Err)
+	}
+	return fmt.Sprintf("zone %d: %v", e.Zone, e.Err)
+}
+
+// PurgeErrors aggregates the failures from a purge batch. It implements
+// error so it can be returned directly, while still giving callers who
+// need the detail a typed slice to range over.
+type PurgeErrors []PurgeError
+
+func (e PurgeErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, pe := range e {
+		msgs[i] = pe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (max *MaxCDN) concurrency() int {
+	if max.Concurrency > 0 {
+		return max.Concurrency
+	}
+	return defaultPurgeConcurrency
+}
+
+// PurgeZones purges multiple zones' caches. It's a backward-compatible
+// wrapper around PurgeZonesContext that discards the detailed
+// per-zone error set in favor of a single error, matching the original
+// signature of this method.
+func (max *MaxCDN) PurgeZones(zones []int) (responses []GenericResponse, last error) {
+	responses, err := max.PurgeZonesContext(context.Background(), zones)
+	return responses, lastOf(err)
+}
+
+// PurgeZonesContext purges multiple zones' caches concurrently, bounded
+// by MaxCDN.Concurrency (default 8). Unlike PurgeZones, it reports every
+// failure: a non-nil error is always a PurgeErrors, letting callers
+// inspect exactly which zones failed and handle partial success.
+func (max *MaxCDN) PurgeZonesContext(ctx context.Context, zones []int) ([]GenericResponse, error) {
+	type result struct {
+		zone int
+		resp *GenericResponse
+		err  error
+	}
+
+	jobs := make(chan int)
+	results := make(chan result, len(zones))
+
+	var wg sync.WaitGroup
+	for i := 0; i < max.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for zone := range jobs {
+				resp, err := max.PurgeZoneContext(ctx, zone)
+				results <- result{zone: zone, resp: resp, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, zone := range zones {
+			select {
+			case jobs <- zone:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var responses []GenericResponse
+	var errs PurgeErrors
+	seen := make(map[int]bool, len(zones))
+	for r := range results {
+		seen[r.zone] = true
+		if r.resp != nil {
+			responses = append(responses, *r.resp)
+		}
+		if r.err != nil {
+			errs = append(errs, PurgeError{Zone: r.zone, Err: r.err})
+		}
+	}
+
+	// Zones the dispatcher never got to send (ctx was canceled or its
+	// deadline elapsed) would otherwise vanish silently instead of
+	// surfacing as failures.
+	if ctx.Err() != nil {
+		for _, zone := range zones {
+			if !seen[zone] {
+				errs = append(errs, PurgeError{Zone: zone, Err: ctx.Err()})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return responses, errs
+	}
+	return responses, nil
+}
+
+// PurgeFiles purges multiple files from a zone. It's a backward-compatible
+// wrapper around PurgeFilesContext that discards the detailed per-file
+// error set in favor of a single error, matching the original signature
+// of this method.
+func (max *MaxCDN) PurgeFiles(zone int, files []string) (responses []GenericResponse, last error) {
+	responses, err := max.PurgeFilesContext(context.Background(), zone, files)
+	return responses, lastOf(err)
+}
+
+// PurgeFilesContext purges multiple files from a zone concurrently,
+// bounded by MaxCDN.Concurrency (default 8). Unlike PurgeFiles, it
+// reports every failure: a non-nil error is always a PurgeErrors,
+// letting callers inspect exactly which files failed and handle partial
+// success.
+func (max *MaxCDN) PurgeFilesContext(ctx context.Context, zone int, files []string) ([]GenericResponse, error) {
+	type result struct {
+		file string
+		resp *GenericResponse
+		err  error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result, len(files))
+
+	var wg sync.WaitGroup
+	for i := 0; i < max.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				resp, err := max.PurgeFileContext(ctx, zone, file)
+				results <- result{file: file, resp: resp, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, file := range files {
+			select {
+			case jobs <- file:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var responses []GenericResponse
+	var errs PurgeErrors
+	seen := make(map[string]bool, len(files))
+	for r := range results {
+		seen[r.file] = true
+		if r.resp != nil {
+			responses = append(responses, *r.resp)
+		}
+		if r.err != nil {
+			errs = append(errs, PurgeError{Zone: zone, File: r.file, Err: r.err})
+		}
+	}
+
+	// Files the dispatcher never got to send (ctx was canceled or its
+	// deadline elapsed) would otherwise vanish silently instead of
+	// surfacing as failures.
+	if ctx.Err() != nil {
+		for _, file := range files {
+			if !seen[file] {
+				errs = append(errs, PurgeError{Zone: zone, File: file, Err: ctx.Err()})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return responses, errs
+	}
+	return responses, nil
+}
+
+// lastOf reduces a PurgeErrors to the error of its last entry, for
+// callers using the pre-PurgeErrors signature. Any other error type is
+// returned unchanged.
+func lastOf(err error) error {
+	if errs, ok := err.(PurgeErrors); ok {
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs[len(errs)-1]
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package maxcdn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestMaxCDN(t *testing.T, handler http.HandlerFunc) *MaxCDN {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	orig := APIHost
+	APIHost = server.URL
+	t.Cleanup(func() { APIHost = orig })
+
+	return NewMaxCDN("test", "token", "secret")
+}
+
+func TestPurgeZonesContext_AggregatesAllErrors(t *testing.T) {
+	max := newTestMaxCDN(t, func(w http.ResponseWriter, r *http.Request) {
+		// Every zone purge fails, so every failure should surface in
+		// PurgeErrors instead of only the last one observed.
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"code":500}`)
+	})
+
+	zones := []int{1, 2, 3, 4, 5}
+	_, err := max.PurgeZonesContext(context.Background(), zones)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	errs, ok := err.(PurgeErrors)
+	if !ok {
+		t.Fatalf("expected PurgeErrors, got %T", err)
+	}
+	if len(errs) != len(zones) {
+		t.Fatalf("expected %d aggregated errors, got %d", len(zones), len(errs))
+	}
+}
+
+func TestPurgeZonesContext_BoundsConcurrency(t *testing.T) {
+	var current, peak int32
+
+	max := newTestMaxCDN(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"code":200}`)
+	})
+	max.Concurrency = 2
+
+	zones := make([]int, 10)
+	for i := range zones {
+		zones[i] = i + 1
+	}
+
+	if _, err := max.PurgeZonesContext(context.Background(), zones); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if peak > int32(max.Concurrency) {
+		t.Fatalf("observed %d concurrent requests, want <= %d", peak, max.Concurrency)
+	}
+}
+
+func TestPurgeZonesContext_SurfacesSkippedZonesOnCancellation(t *testing.T) {
+	max := newTestMaxCDN(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":200}`)
+	})
+	max.Concurrency = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	zones := []int{1, 2, 3}
+	responses, err := max.PurgeZonesContext(ctx, zones)
+	if err == nil {
+		t.Fatal("expected an error for the zones skipped by cancellation")
+	}
+
+	errs, ok := err.(PurgeErrors)
+	if !ok {
+		t.Fatalf("expected PurgeErrors, got %T", err)
+	}
+	if len(errs) != len(zones) {
+		t.Fatalf("expected all %d zones to surface as errors, got %d (responses: %d)", len(zones), len(errs), len(responses))
+	}
+	for _, e := range errs {
+		if !errors.Is(e.Err, context.Canceled) {
+			t.Fatalf("expected an error wrapping %v, got %v", context.Canceled, e.Err)
+		}
+	}
+}
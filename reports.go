@@ -0,0 +1,173 @@
+package maxcdn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// defaultReportPageSize is used by report iterators when
+// ReportOptions.Limit is unset.
+const defaultReportPageSize = 50
+
+// TimeRange narrows a report to the window between Since and Until. A
+// zero Time leaves that end of the range open, matching the API's own
+// "from the beginning"/"through now" defaults.
+type TimeRange struct {
+	Since time.Time
+	Until time.Time
+}
+
+func (t TimeRange) values() url.Values {
+	form := url.Values{}
+	if !t.Since.IsZero() {
+		form.Set("start", t.Since.Format("2006-01-02"))
+	}
+	if !t.Until.IsZero() {
+		form.Set("end", t.Until.Format("2006-01-02"))
+	}
+	return form
+}
+
+// ReportOptions configures a report query.
+type ReportOptions struct {
+	TimeRange
+
+	// Limit is the page size used by paginated reports. It defaults to
+	// defaultReportPageSize when zero or negative.
+	Limit int
+}
+
+func (o ReportOptions) limit() int {
+	if o.Limit > 0 {
+		return o.Limit
+	}
+	return defaultReportPageSize
+}
+
+// PopularFile is a single entry from the popular files report.
+type PopularFile struct {
+	URL   string `json:"url"`
+	Hits  int64  `json:"hits"`
+	Bytes int64  `json:"bytes"`
+}
+
+// HitsMisses is the cache hit/miss breakdown for a zone over a
+// TimeRange.
+type HitsMisses struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// TrafficStat is a single sample from the traffic-by-hour or
+// traffic-by-day report.
+type TrafficStat struct {
+	Timestamp time.Time `json:"timestamp"`
+	Hits      int64     `json:"hits"`
+	Bytes     int64     `json:"bytes"`
+}
+
+// Reports provides typed access to MaxCDN's reporting endpoints, in
+// place of parsing GenericResponse.Data by hand.
+type Reports struct {
+	max *MaxCDN
+}
+
+// PopularFilesIterator transparently paginates the popular files report.
+// Obtain one via Reports.PopularFiles, then call All to consume it.
+type PopularFilesIterator struct {
+	max  *MaxCDN
+	ctx  context.Context
+	zone int
+	opts ReportOptions
+}
+
+// PopularFiles returns an iterator over zone's most popular files,
+// scoped by opts.
+func (r *Reports) PopularFiles(ctx context.Context, zone int, opts ReportOptions) *PopularFilesIterator {
+	return &PopularFilesIterator{max: r.max, ctx: ctx, zone: zone, opts: opts}
+}
+
+// All streams every popular file across all pages on files, closing
+// both channels when exhausted. errs carries at most one error, from
+// either the API or ctx.
+func (it *PopularFilesIterator) All() (files <-chan PopularFile, errs <-chan error) {
+	out := make(chan PopularFile)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		limit := it.opts.limit()
+
+		for page := 1; ; page++ {
+			form := it.opts.TimeRange.values()
+			form.Set("page", strconv.Itoa(page))
+			form.Set("limit", strconv.Itoa(limit))
+
+			res, err := it.max.GetContext(it.ctx, fmt.Sprintf("/reports/popularfiles.json/%d", it.zone), form)
+			if err != nil {
+				errc <- err
+				return
+			}
+
+			var payload struct {
+				Files []PopularFile `json:"files"`
+			}
+			if err := json.Unmarshal(res.Data, &payload); err != nil {
+				errc <- err
+				return
+			}
+
+			for _, f := range payload.Files {
+				select {
+				case out <- f:
+				case <-it.ctx.Done():
+					errc <- it.ctx.Err()
+					return
+				}
+			}
+
+			if len(payload.Files) < limit {
+				return
+			}
+		}
+	}()
+
+	return out, errc
+}
+
+// HitsMisses fetches the cache hit/miss breakdown for zone over opts.
+func (r *Reports) HitsMisses(ctx context.Context, zone int, opts ReportOptions) (*HitsMisses, error) {
+	res, err := r.max.GetContext(ctx, fmt.Sprintf("/reports/stats.json/%d", zone), opts.TimeRange.values())
+	if err != nil {
+		return nil, err
+	}
+
+	stats := new(HitsMisses)
+	if err := json.Unmarshal(res.Data, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Traffic fetches per-hour (or, with a multi-day TimeRange, per-day)
+// traffic samples for zone.
+func (r *Reports) Traffic(ctx context.Context, zone int, opts ReportOptions) ([]TrafficStat, error) {
+	res, err := r.max.GetContext(ctx, fmt.Sprintf("/reports/traffic.json/%d", zone), opts.TimeRange.values())
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Stats []TrafficStat `json:"stats"`
+	}
+	if err := json.Unmarshal(res.Data, &payload); err != nil {
+		return nil, err
+	}
+	return payload.Stats, nil
+}
@@ -0,0 +1,59 @@
+package maxcdn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPopularFilesIterator_PaginatesAcrossPages(t *testing.T) {
+	pages := [][]string{
+		{"/a.js", "/b.js"},
+		{"/c.js"},
+	}
+
+	max := newTestMaxCDN(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+
+		var files string
+		switch page {
+		case "1":
+			files = `{"url":"/a.js","hits":1,"bytes":1},{"url":"/b.js","hits":2,"bytes":2}`
+		case "2":
+			files = `{"url":"/c.js","hits":3,"bytes":3}`
+		default:
+			files = ""
+		}
+
+		fmt.Fprintf(w, `{"code":200,"data":{"files":[%s]}}`, files)
+	})
+	max.Reports = &Reports{max: max}
+
+	got, errc := max.Reports.PopularFiles(context.Background(), 1, ReportOptions{Limit: 2}).All()
+
+	var urls []string
+	for f := range got {
+		urls = append(urls, f.URL)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{}
+	for _, page := range pages {
+		want = append(want, page...)
+	}
+
+	if len(urls) != len(want) {
+		t.Fatalf("expected %d files across pages, got %d: %v", len(want), len(urls), urls)
+	}
+	for i, url := range want {
+		if urls[i] != url {
+			t.Fatalf("file %d: expected %q, got %q", i, url, urls[i])
+		}
+	}
+}
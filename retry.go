@@ -0,0 +1,148 @@
+package maxcdn
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryConfig controls how MaxCDN retries failed requests. The zero
+// value disables retrying.
+type RetryConfig struct {
+	// MaxRetries is the number of additional attempts made after the
+	// initial request. 0 disables retrying.
+	MaxRetries int
+
+	// InitialBackoff is the base delay used for the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+
+	// Jitter enables full-jitter exponential backoff instead of the
+	// capped exponential delay as-is.
+	Jitter bool
+
+	// OnRetry, if set, is called before each retry attempt for logging
+	// or metering.
+	OnRetry func(attempt int, err error, resp *http.Response)
+}
+
+// idempotentMethods are retried automatically by DoContext. POST is
+// excluded by default; use DoWithRetry to opt in per request.
+var idempotentMethods = map[string]bool{
+	"GET":    true,
+	"PUT":    true,
+	"DELETE": true,
+}
+
+// DoWithRetry is like DoContext, but applies cfg regardless of method,
+// letting callers opt POST (or any other method) into retrying for a
+// single call without changing MaxCDN.Retry.
+func (max *MaxCDN) DoWithRetry(ctx context.Context, method, endpoint string, form url.Values, cfg RetryConfig) (raw []byte, res *http.Response, err error) {
+	return max.doWithRetry(ctx, method, endpoint, form, cfg)
+}
+
+func (max *MaxCDN) doWithRetry(ctx context.Context, method, endpoint string, form url.Values, cfg RetryConfig) (raw []byte, res *http.Response, err error) {
+	for attempt := 0; ; attempt++ {
+		raw, res, err = max.doOnce(ctx, method, endpoint, form)
+
+		if attempt >= cfg.MaxRetries || !shouldRetry(err, res) {
+			return raw, res, err
+		}
+
+		if cfg.OnRetry != nil {
+			cfg.OnRetry(attempt+1, err, res)
+		}
+
+		delay := backoffDelay(cfg, attempt)
+		if wait := retryAfterDelay(res); wait > 0 {
+			delay = wait
+		}
+
+		select {
+		case <-ctx.Done():
+			return raw, res, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// shouldRetry reports whether a request should be retried given its
+// error and/or response: transient network errors and 5xx, 429, and
+// 503 responses are retryable.
+func shouldRetry(err error, res *http.Response) bool {
+	if err != nil {
+		return isTransient(err)
+	}
+	if res == nil {
+		return false
+	}
+	return res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+}
+
+// isTransient reports whether err is a connection-level failure worth
+// retrying, as opposed to a permanent error like request validation or
+// context cancellation.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// backoffDelay computes the delay before the given retry attempt
+// (0-indexed) using capped exponential backoff, optionally with full
+// jitter.
+func backoffDelay(cfg RetryConfig, attempt int) time.Duration {
+	cap := cfg.MaxBackoff
+	backoff := cfg.InitialBackoff << uint(attempt)
+	if backoff < 0 || backoff>>uint(attempt) != cfg.InitialBackoff {
+		// The shift overflowed; treat it as "as large as possible"
+		// rather than as the caller's legitimate zero value.
+		backoff = math.MaxInt64
+	}
+	if cap > 0 && backoff > cap {
+		backoff = cap
+	}
+
+	if !cfg.Jitter {
+		return backoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff)))
+}
+
+// retryAfterDelay honors a Retry-After header on 429/503 responses,
+// returning 0 if none is present or it can't be parsed.
+func retryAfterDelay(res *http.Response) time.Duration {
+	if res == nil || (res.StatusCode != http.StatusTooManyRequests && res.StatusCode != http.StatusServiceUnavailable) {
+		return 0
+	}
+
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,196 @@
+package maxcdn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// unreachableHost is nothing listening behind it, so dialing it fails
+// fast with a connection-refused error rather than a timeout.
+const unreachableHost = "http://127.0.0.1:1"
+
+func withUnreachableAPIHost(t *testing.T) {
+	t.Helper()
+	orig := APIHost
+	APIHost = unreachableHost
+	t.Cleanup(func() { APIHost = orig })
+}
+
+func TestDoContext_ConnectionErrorDoesNotPanic(t *testing.T) {
+	withUnreachableAPIHost(t)
+
+	max := NewMaxCDN("alias", "token", "secret")
+	max.HTTPClient.Timeout = 2 * time.Second
+
+	_, _, err := max.DoContext(context.Background(), "GET", "/zones.json", nil)
+	if err == nil {
+		t.Fatal("expected a connection error, got nil")
+	}
+}
+
+func TestDoWithRetry_RetriesConnectionErrors(t *testing.T) {
+	withUnreachableAPIHost(t)
+
+	max := NewMaxCDN("alias", "token", "secret")
+	max.HTTPClient.Timeout = 2 * time.Second
+
+	var retries int
+	cfg := RetryConfig{
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		OnRetry: func(attempt int, err error, resp *http.Response) {
+			retries++
+		},
+	}
+
+	_, _, err := max.DoWithRetry(context.Background(), "GET", "/zones.json", nil, cfg)
+	if err == nil {
+		t.Fatal("expected a connection error, got nil")
+	}
+	if retries != cfg.MaxRetries {
+		t.Fatalf("expected %d retries, got %d", cfg.MaxRetries, retries)
+	}
+}
+
+func TestDoContext_Retries5xxThenSucceeds(t *testing.T) {
+	var attempts int32
+	max := newTestMaxCDN(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"code":500}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":200}`)
+	})
+	max.Retry = RetryConfig{
+		MaxRetries:     1,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+
+	_, res, err := max.DoContext(context.Background(), "GET", "/zones.json", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected an eventual 200, got %d", res.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestDoContext_HonorsRetryAfterHeader(t *testing.T) {
+	var attempts int32
+	var firstAttempt time.Time
+
+	max := newTestMaxCDN(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			firstAttempt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"code":200}`)
+	})
+	max.Retry = RetryConfig{
+		// A short backoff that the Retry-After header should override,
+		// so a too-fast second attempt would fail this test.
+		MaxRetries:     1,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}
+
+	if _, _, err := max.DoContext(context.Background(), "GET", "/zones.json", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(firstAttempt); elapsed < time.Second {
+		t.Fatalf("expected the retry to wait for Retry-After, only waited %s", elapsed)
+	}
+}
+
+func TestDoContext_DoesNotRetryPOSTByDefault(t *testing.T) {
+	var attempts int32
+	max := newTestMaxCDN(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"code":500}`)
+	})
+	max.Retry = RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond}
+
+	if _, _, err := max.DoContext(context.Background(), "POST", "/zones.json", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected POST to be attempted once without retrying, got %d attempts", got)
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		res  *http.Response
+		want bool
+	}{
+		{"200 response", nil, &http.Response{StatusCode: http.StatusOK}, false},
+		{"500 response", nil, &http.Response{StatusCode: http.StatusInternalServerError}, true},
+		{"429 response", nil, &http.Response{StatusCode: http.StatusTooManyRequests}, true},
+		{"nil error, nil response", nil, nil, false},
+		{"context canceled", context.Canceled, nil, false},
+		{"context deadline exceeded", context.DeadlineExceeded, nil, false},
+		{"permanent validation error", errors.New("oauth: url must not contain a query string"), nil, false},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, nil, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := shouldRetry(c.err, c.res); got != c.want {
+				t.Fatalf("shouldRetry(%v, %v) = %v, want %v", c.err, c.res, got, c.want)
+			}
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     RetryConfig
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "zero InitialBackoff stays zero regardless of MaxBackoff",
+			cfg:     RetryConfig{MaxBackoff: 30 * time.Second},
+			attempt: 0,
+			want:    0,
+		},
+		{
+			name:    "exponential growth before the cap",
+			cfg:     RetryConfig{InitialBackoff: time.Second, MaxBackoff: 30 * time.Second},
+			attempt: 2,
+			want:    4 * time.Second,
+		},
+		{
+			name:    "capped once it exceeds MaxBackoff",
+			cfg:     RetryConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second},
+			attempt: 5,
+			want:    5 * time.Second,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := backoffDelay(c.cfg, c.attempt); got != c.want {
+				t.Fatalf("backoffDelay(%+v, %d) = %s, want %s", c.cfg, c.attempt, got, c.want)
+			}
+		})
+	}
+}